@@ -0,0 +1,46 @@
+package context
+
+import "sync"
+
+// afterFuncCtx 把一个回调函数包装成canceler，挂到parent的children里
+// 这样parent.cancel()递归取消子节点时会顺带"取消"它，从而触发回调，
+// 不再需要用户手写 go func(){ <-ctx.Done(); f() }() 这种容易忘记stop而泄漏的写法
+type afterFuncCtx struct {
+	cancelCtx
+
+	once sync.Once // 保证f只会被执行一次
+	f    func()
+}
+
+// AfterFunc 在ctx被done之后，在一个新的goroutine里执行f
+// 如果ctx已经done了，f会立刻在一个新的goroutine里执行
+// 多次对同一个ctx调用AfterFunc互不影响，都会各自执行
+// 返回的stop用于取消这次注册；如果stop在f开始执行前调用，会阻止f执行，此时返回true
+func AfterFunc(ctx Context, f func()) (stop func() bool) {
+	a := &afterFuncCtx{
+		f: f,
+	}
+	a.cancelCtx.Context = ctx
+	propagateCancel(ctx, a)
+	return func() bool {
+		stopped := false
+		a.once.Do(func() {
+			stopped = true
+		})
+		if stopped {
+			a.cancel(true, Canceled, Cause(ctx))
+		}
+		return stopped
+	}
+}
+
+// cancel 覆盖cancelCtx.cancel，在真正取消自身的同时触发回调f
+func (a *afterFuncCtx) cancel(removeFromParent bool, err, cause error) {
+	a.cancelCtx.cancel(false, err, cause)
+	if removeFromParent {
+		removeChild(a.Context, a)
+	}
+	a.once.Do(func() {
+		go a.f()
+	})
+}
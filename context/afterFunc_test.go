@@ -0,0 +1,71 @@
+package context
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAfterFuncRunsOnCancel(t *testing.T) {
+	ctx, cancel := WithCancel(Background())
+	done := make(chan struct{})
+	AfterFunc(ctx, func() { close(done) })
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc callback did not run after cancel")
+	}
+}
+
+func TestAfterFuncRunsImmediatelyIfAlreadyDone(t *testing.T) {
+	ctx, cancel := WithCancel(Background())
+	cancel()
+
+	done := make(chan struct{})
+	AfterFunc(ctx, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc callback did not run for an already-done ctx")
+	}
+}
+
+func TestAfterFuncStopPreventsCallback(t *testing.T) {
+	ctx, cancel := WithCancel(Background())
+	defer cancel()
+
+	called := false
+	stop := AfterFunc(ctx, func() { called = true })
+	if !stop() {
+		t.Fatal("stop() = false, want true when f had not fired yet")
+	}
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	if called {
+		t.Fatal("AfterFunc callback ran after stop() prevented it")
+	}
+}
+
+func TestAfterFuncMultipleRegistrationsAllFire(t *testing.T) {
+	ctx, cancel := WithCancel(Background())
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		AfterFunc(ctx, wg.Done)
+	}
+	cancel()
+
+	waitDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(waitDone)
+	}()
+	select {
+	case <-waitDone:
+	case <-time.After(time.Second):
+		t.Fatal("not all AfterFunc registrations fired")
+	}
+}
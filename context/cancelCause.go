@@ -0,0 +1,30 @@
+package context
+
+// CancelCauseFunc 和CancelFunc类似，但是允许调用方指定一个取消原因
+// 多次调用只有第一次生效，后续调用会被忽略
+type CancelCauseFunc func(cause error)
+
+// WithCancelCause 和WithCancel类似，区别是返回的cancel函数可以附带一个cause
+// Err()依然返回Canceled，保持和WithCancel的行为兼容；
+// 而Cause(ctx)会返回调用cancel时传入的cause，让调用方知道具体是"为什么"被取消的
+func WithCancelCause(parent Context) (ctx Context, cancel CancelCauseFunc) {
+	if parent == nil {
+		panic("不能没有parent context 创建 context")
+	}
+	c := newCancelCtx(parent)
+	propagateCancel(parent, &c)
+	return &c, func(cause error) { c.cancel(true, Canceled, cause) }
+}
+
+// Cause 返回ctx被取消的根本原因
+// 沿着parent链向上查找最近的*cancelCtx，返回它的cause字段；
+// 如果cause为空（例如ctx本身不是通过WithCancelCause取消的），则退化为Err()
+// 如果ctx还没有被取消，返回nil
+func Cause(ctx Context) error {
+	if cc, ok := ctx.Value(&cancelCtxKey).(*cancelCtx); ok {
+		cc.mu.Lock()
+		defer cc.mu.Unlock()
+		return cc.cause
+	}
+	return nil
+}
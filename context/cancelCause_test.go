@@ -0,0 +1,42 @@
+package context
+
+import "testing"
+
+func TestWithCancelCauseErrStaysCanceled(t *testing.T) {
+	ctx, cancel := WithCancelCause(Background())
+	myErr := errorString("boom")
+	cancel(myErr)
+	<-ctx.Done()
+	if ctx.Err() != Canceled {
+		t.Fatalf("Err() = %v, want Canceled", ctx.Err())
+	}
+	if Cause(ctx) != myErr {
+		t.Fatalf("Cause() = %v, want %v", Cause(ctx), myErr)
+	}
+}
+
+func TestWithCancelCauseDefaultsToErr(t *testing.T) {
+	ctx, cancel := WithCancelCause(Background())
+	cancel(nil)
+	if Cause(ctx) != Canceled {
+		t.Fatalf("Cause() = %v, want Canceled when no cause given", Cause(ctx))
+	}
+}
+
+func TestCausePropagatesToChildren(t *testing.T) {
+	parent, cancel := WithCancelCause(Background())
+	child, childCancel := WithCancel(parent)
+	defer childCancel()
+
+	myErr := errorString("root cause")
+	cancel(myErr)
+	<-child.Done()
+
+	if Cause(child) != myErr {
+		t.Fatalf("child Cause() = %v, want %v", Cause(child), myErr)
+	}
+}
+
+type errorString string
+
+func (e errorString) Error() string { return string(e) }
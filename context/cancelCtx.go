@@ -12,6 +12,7 @@ type cancelCtx struct {
 	done     chan struct{}         // 用于获取该Context的取消通知
 	children map[canceler]struct{} // 记录了由此context派生的所有child 此context被取消时会把其中所有的child都`cancel`掉
 	err      error                 // 当被cancel时将会把err设置为 非nil
+	cause    error                 // 取消的根本原因，由WithCancelCause传入，默认等于err
 }
 
 // Done ** 2 **
@@ -49,6 +50,11 @@ func (c *cancelCtx) String() string {
 	return contextName(c.Context) + ".WithCancel"
 }
 
+// parentContext 让Values之类需要穿透非取值层的调用者能拿到parent
+func (c *cancelCtx) parentContext() Context {
+	return c.Context
+}
+
 // 关闭自己及其后代
 // 核心是关闭c.done
 // 同时会设置c.err = err, c.children = nil
@@ -60,18 +66,24 @@ func (c *cancelCtx) String() string {
 // goroutine 接收到取消信号的方式就是 select 语句中的读 c.done 被选中
 //
 // 当removeFromParent为true时，会将当前节点的context从父节点context中删除
-func (c *cancelCtx) cancel(removeFromParent bool, err error) {
+// cause 表示取消的根本原因，未显式指定时（cause为nil）会退化为err本身，
+// 以便通过Cause(ctx)获取比Err()更详细的取消原因
+func (c *cancelCtx) cancel(removeFromParent bool, err, cause error) {
 	// 必须要传err
 	if err == nil {
 		panic("context: internal error: missing cancel error")
 	}
+	if cause == nil {
+		cause = err
+	}
 	c.mu.Lock()
 	if c.err != nil {
 		c.mu.Unlock()
 		return //已经被其他协程取消
 	}
-	// 给err字段赋值
+	// 给err、cause字段赋值
 	c.err = err
+	c.cause = cause
 	// 关闭channel， 通知其他协程
 	if c.done == nil { // 不管怎样，close掉c.done
 		c.done = closedchan
@@ -80,13 +92,16 @@ func (c *cancelCtx) cancel(removeFromParent bool, err error) {
 	}
 	// 遍历它的所有子节点
 	for child := range c.children {
-		// 递归，cancel掉孩子节点
-		child.cancel(false, err)
+		// 递归，cancel掉孩子节点，沿途把cause继续往下传递
+		child.cancel(false, err, cause)
 	}
 	// 将子节点置空
 	c.children = nil
 	c.mu.Unlock()
 
+	// 节点已经被取消，不再需要为它保留调试用的创建现场，避免debugStacks无限增长
+	clearDebugStack(c)
+
 	if removeFromParent {
 		// 从父节点中移除自己
 		removeChild(c.Context, c)
@@ -25,7 +25,7 @@ type Context interface {
 // 实现该接口的类型都可以被直接canceled
 // *cancelCtx 和 *timerCtx 都实现了这个接口
 type canceler interface {
-	cancel(removeFromParent bool, err error)
+	cancel(removeFromParent bool, err, cause error)
 	Done() <-chan struct{}
 }
 
@@ -78,9 +78,9 @@ func WithCancel(parent Context) (ctx Context, cancel CancelFunc) {
 	if parent == nil {
 		panic("不能没有parent context 创建 context")
 	}
-	c := newCancelCtx(parent)                      //  1. 初始化一个cancelCtx实例
-	propagateCancel(parent, &c)                    // 构建父子上下文之间的关联，当父context被取消时，子context也会被取消
-	return &c, func() { c.cancel(true, Canceled) } // 3. 返回cancelCtx实例和cancel方法
+	c := newCancelCtx(parent)                            //  1. 初始化一个cancelCtx实例
+	propagateCancel(parent, &c)                          // 构建父子上下文之间的关联，当父context被取消时，子context也会被取消
+	return &c, func() { c.cancel(true, Canceled, nil) } // 3. 返回cancelCtx实例和cancel方法
 }
 
 // newCancelCtx 返回一个初始化的 cancelCtx
@@ -101,7 +101,7 @@ func propagateCancel(parent Context, child canceler) {
 
 	select {
 	case <-done:
-		child.cancel(false, parent.Err()) // 父context已经被取消时，子context会被立刻取消
+		child.cancel(false, parent.Err(), Cause(parent)) // 父context已经被取消时，子context会被立刻取消
 		return
 	default:
 	}
@@ -110,7 +110,7 @@ func propagateCancel(parent Context, child canceler) {
 		p.mu.Lock()
 		if p.err != nil {
 			// 父context已经被取消，本节点也要取消
-			child.cancel(false, p.err)
+			child.cancel(false, p.err, p.cause)
 		} else {
 			// 父节点未取消
 			if p.children == nil {
@@ -118,15 +118,17 @@ func propagateCancel(parent Context, child canceler) {
 			}
 			// "挂到"父节点上
 			p.children[child] = struct{}{}
+			recordEdge(child)
 		}
 		p.mu.Unlock()
 	} else {
 		atomic.AddInt32(&goroutines, +1)
+		recordEdge(child)
 		// 如果没有找到可取消的父 context。新启动一个协程监控父节点或子节点取消信号
 		go func() {
 			select {
 			case <-parent.Done(): // 当parent.Done() 关闭时调用child.cancel 取消上下文
-				child.cancel(false, parent.Err())
+				child.cancel(false, parent.Err(), Cause(parent))
 			case <-child.Done():
 			}
 		}()
@@ -157,17 +159,17 @@ func WithDeadline(parent Context, d time.Time) (Context, CancelFunc) {
 	propagateCancel(parent, c)
 	dur := time.Until(d)
 	if dur <= 0 {
-		c.cancel(true, DeadlineExceeded)
-		return c, func() { c.cancel(false, Canceled) }
+		c.cancel(true, DeadlineExceeded, nil)
+		return c, func() { c.cancel(false, Canceled, nil) }
 	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.err == nil {
 		c.timer = time.AfterFunc(dur, func() {
-			c.cancel(true, DeadlineExceeded)
+			c.cancel(true, DeadlineExceeded, nil)
 		})
 	}
-	return c, func() { c.cancel(true, Canceled) }
+	return c, func() { c.cancel(true, Canceled, nil) }
 }
 
 // WithTimeout 和WithDeadline基本一样，表示超时自动取消
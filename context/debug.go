@@ -0,0 +1,169 @@
+package context
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugMu 保护下面这些调试相关的全局状态
+var (
+	debugMu     sync.Mutex
+	debugOn     bool
+	debugStacks = map[*cancelCtx]string{} // 以节点自身的*cancelCtx为key，记录创建时的调用栈
+)
+
+// SetDebug 打开或者关闭context树的调试模式
+// 打开之后，每次propagateCancel建立父子关系时都会额外记录一份创建现场的调用栈，
+// 可以配合Dump/Tree在例如"/debug/context"这样的HTTP handler里定期查看，
+// 从而发现那些忘记调用cancel、一直挂着没有被回收的context子树（也就是常说的"context泄漏"）
+// 注意：开启调试模式会带来额外的开销，不建议在生产环境常驻打开
+func SetDebug(enabled bool) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	debugOn = enabled
+	if !enabled {
+		debugStacks = map[*cancelCtx]string{}
+	}
+}
+
+// cancelCtxOf 找到child自身对应的*cancelCtx节点，
+// 不管child是cancelCtx本身还是afterFuncCtx/mergeCtx这类内嵌了cancelCtx的包装类型
+func cancelCtxOf(child canceler) *cancelCtx {
+	ctx, ok := child.(Context)
+	if !ok {
+		return nil
+	}
+	cc, ok := ctx.Value(&cancelCtxKey).(*cancelCtx)
+	if !ok {
+		return nil
+	}
+	return cc
+}
+
+// recordEdge 在debug模式打开时，记录一条parent->child边的创建现场
+func recordEdge(child canceler) {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	if !debugOn {
+		return
+	}
+	cc := cancelCtxOf(child)
+	if cc == nil {
+		return
+	}
+	buf := make([]byte, 4096)
+	n := runtime.Stack(buf, false)
+	debugStacks[cc] = string(buf[:n])
+}
+
+// clearDebugStack 在节点被cancel掉之后清理它的创建现场记录
+// 否则debugStacks会一直强引用每一个创建过的canceler，本身就造出一个无界的泄漏，
+// 这与这个子系统本来是用来发现泄漏的初衷是矛盾的
+func clearDebugStack(cc *cancelCtx) {
+	debugMu.Lock()
+	delete(debugStacks, cc)
+	debugMu.Unlock()
+}
+
+func creationSite(cc *cancelCtx) string {
+	debugMu.Lock()
+	defer debugMu.Unlock()
+	return debugStacks[cc]
+}
+
+// NodeInfo 描述了context树中的一个节点，用于Dump/Tree的输出
+type NodeInfo struct {
+	Type        string    // cancelCtx/timerCtx/valueCtx等
+	Deadline    time.Time // 如果有deadline
+	HasDeadline bool
+	Err         error  // 当前的Err()
+	NumChildren int    // 存活的子节点数量
+	CreatedAt   string // 创建现场的调用栈，需要SetDebug(true)才会有内容
+	Depth       int    // 在树中的深度，root为0，方便按缩进还原层级
+}
+
+// Tree 返回root为根的context子树中，每个节点的NodeInfo，按照深度优先遍历展开
+func Tree(root Context) []NodeInfo {
+	var nodes []NodeInfo
+	walkTree(root, 0, &nodes)
+	return nodes
+}
+
+func walkTree(c Context, depth int, nodes *[]NodeInfo) {
+	// 只有c自己就是canceler（cancelCtx/afterFuncCtx/mergeCtx这些自带Done/cancel的类型）
+	// 才应该把Value(&cancelCtxKey)的结果当成c自己的节点来展开
+	// 否则像valueCtx这种纯转发层，Value(&cancelCtxKey)会穿透到它的某个可取消祖先，
+	// 如果不做这层判断，会把祖先的Err()/children/子树误报成c自己的
+	if _, ok := c.(canceler); !ok {
+		*nodes = append(*nodes, NodeInfo{
+			Type:  ctxTypeName(c),
+			Depth: depth,
+		})
+		return
+	}
+	cc, ok := c.Value(&cancelCtxKey).(*cancelCtx)
+	if !ok {
+		*nodes = append(*nodes, NodeInfo{
+			Type:  ctxTypeName(c),
+			Depth: depth,
+		})
+		return
+	}
+
+	cc.mu.Lock()
+	info := NodeInfo{
+		Type:        ctxTypeName(c),
+		Err:         cc.err,
+		NumChildren: len(cc.children),
+		Depth:       depth,
+	}
+	children := make([]canceler, 0, len(cc.children))
+	for child := range cc.children {
+		children = append(children, child)
+	}
+	cc.mu.Unlock()
+
+	if d, ok := c.Deadline(); ok {
+		info.Deadline = d
+		info.HasDeadline = true
+	}
+	info.CreatedAt = creationSite(cc)
+	*nodes = append(*nodes, info)
+
+	for _, child := range children {
+		if childCtx, ok := child.(Context); ok {
+			walkTree(childCtx, depth+1, nodes)
+		}
+	}
+}
+
+// ctxTypeName 返回c的具体类型名（指针类型取其元素类型），例如cancelCtx、timerCtx、valueCtx
+func ctxTypeName(c Context) string {
+	t := reflect.TypeOf(c)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Dump 把Tree(root)的结果渲染成一段便于阅读的文本，每一层用两个空格缩进
+func Dump(root Context) string {
+	var b strings.Builder
+	for _, n := range Tree(root) {
+		b.WriteString(strings.Repeat("  ", n.Depth))
+		b.WriteString(n.Type)
+		if n.HasDeadline {
+			fmt.Fprintf(&b, " deadline=%s", n.Deadline.Format(time.RFC3339))
+		}
+		fmt.Fprintf(&b, " err=%v children=%d", n.Err, n.NumChildren)
+		b.WriteByte('\n')
+		if n.CreatedAt != "" {
+			b.WriteString(n.CreatedAt)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package context
+
+import "testing"
+
+func TestDebugStacksClearedAfterCancel(t *testing.T) {
+	SetDebug(true)
+	defer SetDebug(false)
+
+	parent, parentCancel := WithCancel(Background())
+	defer parentCancel()
+	_, cancel := WithCancel(parent)
+
+	if len(debugStacks) == 0 {
+		t.Fatal("expected a recorded edge before cancel")
+	}
+	cancel()
+	if len(debugStacks) != 0 {
+		t.Fatalf("expected debugStacks to be cleared after cancel, got %d entries", len(debugStacks))
+	}
+}
+
+func TestTreeReportsChildCount(t *testing.T) {
+	root, cancel := WithCancel(Background())
+	defer cancel()
+	_, childCancel := WithCancel(root)
+	defer childCancel()
+
+	nodes := Tree(root)
+	if len(nodes) == 0 {
+		t.Fatal("Tree(root) returned no nodes")
+	}
+	if nodes[0].NumChildren != 1 {
+		t.Fatalf("root NumChildren = %d, want 1", nodes[0].NumChildren)
+	}
+}
+
+func TestTreeOnValueCtxDoesNotBorrowAncestor(t *testing.T) {
+	parent, cancel := WithCancel(Background())
+	defer cancel()
+	_, siblingCancel := WithCancel(parent)
+	defer siblingCancel()
+
+	vctx := WithValue(parent, "k", "v")
+	nodes := Tree(vctx)
+	if len(nodes) != 1 {
+		t.Fatalf("want 1 node for a valueCtx root, got %d: %+v", len(nodes), nodes)
+	}
+	if nodes[0].NumChildren != 0 {
+		t.Fatalf("valueCtx root must not report its cancelable ancestor's children, got %+v", nodes[0])
+	}
+}
@@ -0,0 +1,33 @@
+package context
+
+import "time"
+
+// emptyCtx 是Background和TODO底层使用的类型：不可取消、没有deadline、不携带任何值
+// background和todo各自是指向一个emptyCtx零值的指针，靠地址本身区分身份，本身不存储任何状态
+type emptyCtx int
+
+func (*emptyCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (*emptyCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (*emptyCtx) Err() error {
+	return nil
+}
+
+func (*emptyCtx) Value(key interface{}) interface{} {
+	return nil
+}
+
+func (e *emptyCtx) String() string {
+	switch e {
+	case background:
+		return "context.Background"
+	case todo:
+		return "context.TODO"
+	}
+	return "unknown empty Context"
+}
@@ -0,0 +1,153 @@
+package context
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// mergeCtx 和cancelCtx类似，但是它没有单一的parent，而是同时挂在多个parents下面，
+// 任意一个parent被取消，mergeCtx都会被取消
+type mergeCtx struct {
+	cancelCtx
+
+	parents []Context // 保留所有parent，供Value()按照从左到右的顺序查找
+
+	// attachedParents 记录了通过"挂到parent.children"这条路径建立起联系的那些*cancelCtx
+	// 用monitor goroutine那条路径建立联系的parent不在这里面，因为它们本来就不持有对child的强引用，
+	// 不存在需要摘除的问题；只在MergeCancel构造期间写入一次，之后只读，不需要额外加锁
+	attachedParents []*cancelCtx
+}
+
+// Value 依次在parents中查找key，找到第一个非nil的结果就返回
+func (c *mergeCtx) Value(key interface{}) interface{} {
+	if key == &cancelCtxKey {
+		return &c.cancelCtx
+	}
+	for _, p := range c.parents {
+		if v := p.Value(key); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// MergeCancel 返回一个context，只要parents中任意一个被Done，或者返回的cancel被调用，
+// 这个context就会被Done；Err()会返回第一个触发取消的parent的Err()
+// Value()的查找按照parents从左到右的顺序进行
+// 这直接支持"HTTP请求的ctx或者应用退出的ctx任意一个触发就要取消"这种常见场景，
+// 不再需要手写channel去拼接多个取消信号
+func MergeCancel(parents ...Context) (Context, CancelFunc) {
+	for _, p := range parents {
+		if p == nil {
+			panic("不能没有parent context 创建 context")
+		}
+	}
+	c := &mergeCtx{
+		parents: parents,
+	}
+	c.cancelCtx.Context = Background()
+	for _, p := range parents {
+		if pc, ok := propagateCancelMulti(p, c); ok {
+			c.attachedParents = append(c.attachedParents, pc)
+		}
+	}
+	// 某个parent在构造时就已经done的话，c在循环中途就被取消了，
+	// 之后才挂上去的parent不会再被c.cancel摘掉，这里补摘一次
+	if c.Err() != nil {
+		for _, p := range c.attachedParents {
+			detachFromParent(p, c)
+		}
+	}
+	return c, func() { c.cancel(true, Canceled, nil) }
+}
+
+// propagateCancelMulti 和propagateCancel基本一致，区别是它不会把child挂在唯一的parent下，
+// 而是面向MergeCancel的场景：为传入的单个parent建立一条"parent取消就取消child"的通路，
+// 对每一个parent分别调用一次即可实现"多对一"的取消传播
+// 返回值是child被挂到的那个*cancelCtx（如果走的是map-attach这条路径），
+// 调用方需要记下它，以便在child被取消时把自己从这个parent的children里摘掉，否则会一直占着位置
+func propagateCancelMulti(parent Context, child canceler) (attachedTo *cancelCtx, attached bool) {
+	done := parent.Done()
+	if done == nil {
+		return nil, false // 这个parent不会触发取消信号
+	}
+
+	select {
+	case <-done:
+		child.cancel(false, parent.Err(), Cause(parent)) // parent已经取消，child立刻取消
+		return nil, false
+	default:
+	}
+
+	if p, ok := parentCancelCtx(parent); ok {
+		p.mu.Lock()
+		if p.err != nil {
+			child.cancel(false, p.err, p.cause)
+			p.mu.Unlock()
+			return nil, false
+		}
+		if p.children == nil {
+			p.children = make(map[canceler]struct{})
+		}
+		p.children[child] = struct{}{}
+		recordEdge(child)
+		p.mu.Unlock()
+		return p, true
+	}
+
+	atomic.AddInt32(&goroutines, +1)
+	recordEdge(child)
+	go func() {
+		select {
+		case <-parent.Done():
+			child.cancel(false, parent.Err(), Cause(parent))
+		case <-child.Done():
+		}
+	}()
+	return nil, false
+}
+
+// cancel 覆盖cancelCtx.cancel：mergeCtx没有单一的parent，"从parent里移除自己"
+// 意味着要从每一个attachedParents里都摘掉自己，而不只是摘掉c.cancelCtx.Context这个占位用的Background()
+//
+// 这里不能直接对attachedParents里的每个*cancelCtx同步调用removeChild：如果这次cancel正是由
+// 其中某个parent自己的cancel()递归触发的，那个parent的mu已经被同一个goroutine持有，
+// 同步再次Lock会自己把自己死锁。用TryLock探测一下：能拿到锁就直接摘掉；拿不到（基本就是
+// 正在递归的那个parent）就另起一个goroutine等锁释放后再摘，避免阻塞当前的取消流程
+func (c *mergeCtx) cancel(removeFromParent bool, err, cause error) {
+	c.cancelCtx.cancel(false, err, cause)
+	for _, p := range c.attachedParents {
+		detachFromParent(p, c)
+	}
+}
+
+func detachFromParent(p *cancelCtx, child canceler) {
+	if p.mu.TryLock() {
+		if p.children != nil {
+			delete(p.children, child)
+		}
+		p.mu.Unlock()
+		return
+	}
+	go func() {
+		p.mu.Lock()
+		if p.children != nil {
+			delete(p.children, child)
+		}
+		p.mu.Unlock()
+	}()
+}
+
+// Deadline 没有单一的parent概念，mergeCtx本身不携带deadline
+func (c *mergeCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (c *mergeCtx) String() string {
+	return "context.MergeCancel"
+}
+
+// parentContexts 让Values之类需要穿透非取值层的调用者能拿到所有parent
+func (c *mergeCtx) parentContexts() []Context {
+	return c.parents
+}
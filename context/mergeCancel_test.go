@@ -0,0 +1,92 @@
+package context
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeCancelDoneWhenEitherParentFires(t *testing.T) {
+	p1, cancel1 := WithCancel(Background())
+	defer cancel1()
+	p2, cancel2 := WithCancel(Background())
+	defer cancel2()
+
+	merged, cancel := MergeCancel(p1, p2)
+	defer cancel()
+
+	cancel1()
+	<-merged.Done()
+	if merged.Err() != Canceled {
+		t.Fatalf("Err() = %v, want Canceled", merged.Err())
+	}
+}
+
+func TestMergeCancelOwnCancelWorks(t *testing.T) {
+	p1, cancel1 := WithCancel(Background())
+	defer cancel1()
+	p2, cancel2 := WithCancel(Background())
+	defer cancel2()
+
+	merged, cancel := MergeCancel(p1, p2)
+	cancel()
+	select {
+	case <-merged.Done():
+	default:
+		t.Fatal("merged ctx should be done right after its own cancel is called")
+	}
+}
+
+// TestMergeCancelDetachesFromParentChildren 复现chunk0-4的children map泄漏：
+// MergeCancel挂到一个真实的*cancelCtx parent上之后，parent的children里多出一条记录；
+// 如果cancel之后这条记录一直不摘掉，这个parent（典型场景下是进程级别的shutdown ctx）的
+// children会随着每一次请求一直增长，永远不会被回收
+func TestMergeCancelDetachesFromParentChildren(t *testing.T) {
+	p1, cancel1 := WithCancel(Background())
+	defer cancel1()
+
+	merged, cancel := MergeCancel(p1)
+
+	cc, ok := p1.(*cancelCtx)
+	if !ok {
+		t.Fatalf("p1 is not *cancelCtx: %T", p1)
+	}
+	mergedCanceler, ok := merged.(canceler)
+	if !ok {
+		t.Fatalf("merged is not a canceler: %T", merged)
+	}
+
+	cc.mu.Lock()
+	_, attached := cc.children[mergedCanceler]
+	cc.mu.Unlock()
+	if !attached {
+		t.Fatal("expected merged ctx to be attached to p1's children before cancel")
+	}
+
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		cc.mu.Lock()
+		_, stillAttached := cc.children[mergedCanceler]
+		cc.mu.Unlock()
+		if !stillAttached {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("merged ctx was not removed from p1's children after cancel")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMergeCancelValueLeftToRight(t *testing.T) {
+	p1 := WithValue(Background(), "k", "from-p1")
+	p2 := WithValue(Background(), "k", "from-p2")
+
+	merged, cancel := MergeCancel(p1, p2)
+	defer cancel()
+
+	if got := merged.Value("k"); got != "from-p1" {
+		t.Fatalf("Value(%q) = %v, want value from the first parent that has it", "k", got)
+	}
+}
@@ -0,0 +1,34 @@
+package context
+
+import "time"
+
+// timerCtx 在cancelCtx的基础上多了一个deadline，到期后会自动触发cancel
+// 由WithDeadline/WithTimeout创建
+type timerCtx struct {
+	cancelCtx
+
+	timer    *time.Timer // 到达deadline时触发cancel，由WithDeadline设置
+	deadline time.Time
+}
+
+func (c *timerCtx) Deadline() (deadline time.Time, ok bool) {
+	return c.deadline, true
+}
+
+func (c *timerCtx) String() string {
+	return contextName(c.cancelCtx.Context) + ".WithDeadline"
+}
+
+// cancel 覆盖cancelCtx.cancel，在取消自身的同时停掉还没有触发的定时器，避免定时器goroutine泄漏
+func (c *timerCtx) cancel(removeFromParent bool, err, cause error) {
+	c.cancelCtx.cancel(false, err, cause)
+	if removeFromParent {
+		removeChild(c.cancelCtx.Context, c)
+	}
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+}
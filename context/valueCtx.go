@@ -0,0 +1,23 @@
+package context
+
+import "fmt"
+
+// valueCtx 携带一对key/value的Context，其余方法都透传给parent
+// 通过WithValue创建，多个valueCtx可以一层一层叠加，形成一条"值链"
+type valueCtx struct {
+	Context
+	key, val interface{}
+}
+
+// Value ** 和cancelCtx.Value类似 **
+// 先比较自己这一层的key，不匹配再交给parent继续找
+func (c *valueCtx) Value(key interface{}) interface{} {
+	if c.key == key {
+		return c.val
+	}
+	return c.Context.Value(key)
+}
+
+func (c *valueCtx) String() string {
+	return fmt.Sprintf("%v.WithValue(%v, %v)", contextName(c.Context), c.key, c.val)
+}
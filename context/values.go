@@ -0,0 +1,63 @@
+package context
+
+// singleParented 由只有一个parent的包装类型实现（cancelCtx、withoutCancelCtx...），
+// 用于让Values能够穿过这些"非取值层"继续往上走，而不是在第一个非valueCtx节点处就停下
+type singleParented interface {
+	parentContext() Context
+}
+
+// multiParented 由MergeCancel这种有多个parent的包装类型实现
+type multiParented interface {
+	parentContexts() []Context
+}
+
+// KeyValue 是Values遍历时产出的一对键值
+type KeyValue struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Values 返回ctx链上所有通过WithValue挂上去的(key, value)对，按照从子到父的顺序排列
+// 同一个key如果被多层WithValue覆盖，离ctx更近的那一层会先出现在结果里，
+// 调用方可以用这个顺序实现"离ctx最近的值优先"的覆盖语义
+//
+// ctx链上除了valueCtx之外，还常常夹杂着WithCancel/WithTimeout/MergeCancel这些不持有值、
+// 只是转发Value()调用的节点，Values会穿过它们继续向上找，而不会在第一个非valueCtx节点处停下
+//
+// 典型用法是在中间件里把请求上所有的值一次性拷贝进日志记录，
+// 或者在跨RPC边界时把它们整体透传成header，而不需要提前知道ctx上到底挂了哪些key
+//
+// 之所以返回[]KeyValue而不是iter.Seq2，是为了不给这个包引入Go 1.23才有的iter标准库依赖
+func Values(ctx Context) []KeyValue {
+	var out []KeyValue
+	collectValues(ctx, &out)
+	return out
+}
+
+func collectValues(ctx Context, out *[]KeyValue) {
+	for ctx != nil {
+		if vc, ok := ctx.(*valueCtx); ok {
+			*out = append(*out, KeyValue{Key: vc.key, Value: vc.val})
+			ctx = vc.Context
+			continue
+		}
+		if mp, ok := ctx.(multiParented); ok {
+			for _, p := range mp.parentContexts() {
+				collectValues(p, out)
+			}
+			return
+		}
+		if sp, ok := ctx.(singleParented); ok {
+			ctx = sp.parentContext()
+			continue
+		}
+		return
+	}
+}
+
+// TypedValue 是Value(key)的泛型封装，省去了调用方自己做类型断言的麻烦
+// 返回的bool表示ctx链上是否存在该key，并且对应的值可以被断言为T
+func TypedValue[T any](ctx Context, key interface{}) (T, bool) {
+	v, ok := ctx.Value(key).(T)
+	return v, ok
+}
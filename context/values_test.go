@@ -0,0 +1,46 @@
+package context
+
+import "testing"
+
+func TestValuesOrderChildToParent(t *testing.T) {
+	ctx := WithValue(WithValue(Background(), "a", 1), "b", 2)
+	got := Values(ctx)
+	if len(got) != 2 || got[0].Key != "b" || got[1].Key != "a" {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+}
+
+func TestValuesTraversesThroughCancelAndMerge(t *testing.T) {
+	v1 := WithValue(Background(), "a", 1)
+	c, cancel := WithCancel(v1)
+	defer cancel()
+	v2 := WithValue(c, "b", 2)
+
+	got := Values(v2)
+	if len(got) != 2 {
+		t.Fatalf("Values should walk through the WithCancel layer, got %+v", got)
+	}
+
+	p2 := WithValue(Background(), "c", 3)
+	merged, mergeCancel := MergeCancel(v2, p2)
+	defer mergeCancel()
+
+	got = Values(merged)
+	if len(got) != 3 {
+		t.Fatalf("Values should walk through MergeCancel's multiple parents, got %+v", got)
+	}
+}
+
+func TestTypedValue(t *testing.T) {
+	ctx := WithValue(Background(), "n", 42)
+
+	if v, ok := TypedValue[int](ctx, "n"); !ok || v != 42 {
+		t.Fatalf("TypedValue[int] = %v, %v, want 42, true", v, ok)
+	}
+	if _, ok := TypedValue[string](ctx, "n"); ok {
+		t.Fatal("TypedValue[string] should fail the type assertion for an int value")
+	}
+	if _, ok := TypedValue[int](ctx, "missing"); ok {
+		t.Fatal("TypedValue should report false for a key that was never set")
+	}
+}
@@ -0,0 +1,49 @@
+package context
+
+import "time"
+
+// withoutCancelCtx 包装了一个parent，只用于Value()的查找，
+// 它自身永远不会被取消、没有deadline，也不会把Done/Err等信号透传给后代
+type withoutCancelCtx struct {
+	c Context
+}
+
+func (withoutCancelCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (withoutCancelCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (withoutCancelCtx) Err() error {
+	return nil
+}
+
+func (c withoutCancelCtx) Value(key interface{}) interface{} {
+	// 不把parent的*cancelCtx透出去，否则Cause(ctx)会读到parent的取消原因
+	if key == &cancelCtxKey {
+		return nil
+	}
+	return c.c.Value(key)
+}
+
+func (c withoutCancelCtx) String() string {
+	return contextName(c.c) + ".WithoutCancel"
+}
+
+// parentContext 让Values之类需要穿透非取值层的调用者能拿到parent
+func (c withoutCancelCtx) parentContext() Context {
+	return c.c
+}
+
+// WithoutCancel 返回一个不会被取消的parent副本
+// 返回的Context的Done()返回nil、Deadline()返回零值、Err()永远返回nil，
+// 但Value()查找仍然沿用parent，常用于"请求已经结束，但还要用请求里的trace-id/user-id
+// 启动一个不应该被请求取消打断的清理/审计goroutine"这种场景
+func WithoutCancel(parent Context) Context {
+	if parent == nil {
+		panic("不能没有parent context 创建 context")
+	}
+	return withoutCancelCtx{c: parent}
+}
@@ -0,0 +1,32 @@
+package context
+
+import "testing"
+
+func TestWithoutCancelDetachesCancellation(t *testing.T) {
+	parent, cancel := WithCancel(WithValue(Background(), "k", "v"))
+	detached := WithoutCancel(parent)
+
+	if detached.Done() != nil {
+		t.Fatalf("Done() = %v, want nil", detached.Done())
+	}
+	if d, ok := detached.Deadline(); ok {
+		t.Fatalf("Deadline() = %v, true, want zero, false", d)
+	}
+	if detached.Err() != nil {
+		t.Fatalf("Err() = %v, want nil", detached.Err())
+	}
+
+	cancel()
+	<-parent.Done()
+	if detached.Err() != nil {
+		t.Fatalf("Err() after parent cancel = %v, want nil", detached.Err())
+	}
+}
+
+func TestWithoutCancelPreservesValues(t *testing.T) {
+	parent := WithValue(Background(), "k", "v")
+	detached := WithoutCancel(parent)
+	if got := detached.Value("k"); got != "v" {
+		t.Fatalf("Value(%q) = %v, want %q", "k", got, "v")
+	}
+}